@@ -1,24 +1,30 @@
 package main
 
 import (
-	"bytes"
+	"encoding/base64"
 	"encoding/json"
 	"flag"
 	"fmt"
 	"net/url"
 	"os"
+	"strconv"
 	"strings"
 
 	"code.cloudfoundry.org/cli/cf/terminal"
 	"code.cloudfoundry.org/cli/plugin"
 	"code.cloudfoundry.org/cli/plugin/models"
+	"gopkg.in/yaml.v2"
 )
 
 type DescribePlugin struct {
 	cliConnection plugin.CliConnection
+	capi          CAPIClient
 	brokerName    string
 	serviceName   string
 	showGuids     bool
+	renderer      Renderer
+	asUser        bool
+	admin         bool
 }
 
 func (d *DescribePlugin) GetMetadata() plugin.PluginMetadata {
@@ -39,11 +45,16 @@ func (d *DescribePlugin) GetMetadata() plugin.PluginMetadata {
 				Name:     "describe",
 				HelpText: "Show information about brokers or service instances",
 				UsageDetails: plugin.Usage{
-					Usage: "cf describe [-b broker-name] [-s service-instance-name]",
+					Usage: "cf describe [-b broker-name] [-s service-instance-name] [-o text|json|yaml] [-api-version v2|v3]",
 					Options: map[string]string{
-						"-b":          "The name of the broker",
-						"-s":          "The name of the service instance",
-						"-show-guids": "If set, will display the service instances guid",
+						"-b":           "The name of the broker",
+						"-s":           "The name of the service instance",
+						"-show-guids":  "If set, will display the service instances guid",
+						"-o":           "Output format: text (default), json or yaml",
+						"--output":     "Output format: text (default), json or yaml",
+						"-api-version": "Cloud Controller API to talk to: v2 or v3 (default: auto-detect)",
+						"-as-user":     "Only show what the current user can see (default: true for non-admins)",
+						"-admin":       "Skip the visibility filter and show everything, like an admin",
 					},
 				},
 			},
@@ -56,6 +67,11 @@ func (d *DescribePlugin) ParseFlags(args []string) {
 	brokerName := flagSet.String("b", "", "-b <broker-name>")
 	serviceInstanceName := flagSet.String("s", "", "-s <service-instance-name>")
 	showGuids := flagSet.Bool("show-guids", false, "")
+	output := flagSet.String("o", "text", "-o text|json|yaml")
+	flagSet.StringVar(output, "output", "text", "--output text|json|yaml")
+	apiVersion := flagSet.String("api-version", "", "-api-version v2|v3")
+	asUser := flagSet.Bool("as-user", true, "")
+	admin := flagSet.Bool("admin", false, "")
 
 	err := flagSet.Parse(args[1:])
 	if err != nil {
@@ -65,6 +81,10 @@ func (d *DescribePlugin) ParseFlags(args []string) {
 	d.brokerName = *brokerName
 	d.serviceName = *serviceInstanceName
 	d.showGuids = *showGuids
+	d.renderer = newRenderer(*output)
+	d.capi = newCAPIClient(d.cliConnection, *apiVersion)
+	d.asUser = *asUser
+	d.admin = *admin
 }
 
 func (d *DescribePlugin) Run(cliConnection plugin.CliConnection, args []string) {
@@ -83,66 +103,967 @@ func (d *DescribePlugin) Run(cliConnection plugin.CliConnection, args []string)
 	}
 }
 
-type CurlResponse struct {
-	TotalResults int `json:"total_results"`
-	Resources    []struct {
-		Metadata map[string]interface{} `json:"metadata"`
-		Entity   map[string]interface{} `json:"entity"`
-	} `json:"resources"`
+// LastOperationReport mirrors a CC last_operation block.
+type LastOperationReport struct {
+	Type        string `json:"type,omitempty" yaml:"type,omitempty"`
+	State       string `json:"state,omitempty" yaml:"state,omitempty"`
+	Description string `json:"description,omitempty" yaml:"description,omitempty"`
+	UpdatedAt   string `json:"updated_at,omitempty" yaml:"updated_at,omitempty"`
+}
+
+// InstanceReport describes a single service instance as listed under a plan.
+type InstanceReport struct {
+	GUID          string              `json:"guid,omitempty" yaml:"guid,omitempty"`
+	Name          string              `json:"name" yaml:"name"`
+	Org           string              `json:"org" yaml:"org"`
+	Space         string              `json:"space" yaml:"space"`
+	LastOperation LastOperationReport `json:"last_operation" yaml:"last_operation"`
+}
+
+// PlanReport groups the instances provisioned against a single plan.
+type PlanReport struct {
+	Name      string           `json:"name" yaml:"name"`
+	GUID      string           `json:"guid,omitempty" yaml:"guid,omitempty"`
+	Instances []InstanceReport `json:"instances" yaml:"instances"`
+}
+
+// BrokerReport is the full result of `cf describe -b`.
+type BrokerReport struct {
+	Name   string       `json:"name" yaml:"name"`
+	Plans  []PlanReport `json:"plans" yaml:"plans"`
+	Hidden string       `json:"hidden,omitempty" yaml:"hidden,omitempty"`
+}
+
+// BoundAppReport describes an app bound to a service instance.
+type BoundAppReport struct {
+	Name        string `json:"name" yaml:"name"`
+	BindingGUID string `json:"binding_guid,omitempty" yaml:"binding_guid,omitempty"`
+}
+
+// ServiceReport is the full result of `cf describe -s`.
+type ServiceReport struct {
+	GUID            string              `json:"guid,omitempty" yaml:"guid,omitempty"`
+	Name            string              `json:"name" yaml:"name"`
+	Broker          string              `json:"broker" yaml:"broker"`
+	BrokerGUID      string              `json:"broker_guid,omitempty" yaml:"broker_guid,omitempty"`
+	ServiceOffering string              `json:"service_offering" yaml:"service_offering"`
+	Plan            string              `json:"plan" yaml:"plan"`
+	PlanGUID        string              `json:"plan_guid,omitempty" yaml:"plan_guid,omitempty"`
+	DashboardURL    string              `json:"dashboard_url,omitempty" yaml:"dashboard_url,omitempty"`
+	Tags            []string            `json:"tags,omitempty" yaml:"tags,omitempty"`
+	LastOperation   LastOperationReport `json:"last_operation" yaml:"last_operation"`
+	BoundApps       []BoundAppReport    `json:"bound_apps,omitempty" yaml:"bound_apps,omitempty"`
 }
 
 func (d *DescribePlugin) DescribeBroker() {
-	curlResponse := d.curl(fmt.Sprintf("/v2/service_brokers?q=name:%s", url.QueryEscape(d.brokerName)))
-	if curlResponse.TotalResults == 0 {
-		Warn(d.brokerName + " not found")
+	broker, err := d.capi.GetBroker(d.brokerName)
+	if err != nil {
+		d.renderer.Fail(err, "could not resolve broker "+d.brokerName)
+	}
+	if broker.GUID == "" {
+		d.renderer.Warn(d.brokerName + " not found")
+	}
+
+	plans, err := d.capi.ListPlans(broker.GUID)
+	if err != nil {
+		d.renderer.Fail(err, "could not list plans for broker "+d.brokerName)
+	}
+	if len(plans) == 0 {
+		d.renderer.Warn(d.brokerName + " has no plans")
+	}
+
+	visibility := d.newVisibilityFilter()
+
+	report := BrokerReport{Name: d.brokerName}
+
+	for _, plan := range plans {
+		instances, err := d.capi.ListInstances(plan.GUID)
+		if err != nil {
+			d.reportCCError(err, "could not list instances for plan "+plan.Name)
+			continue
+		}
+
+		planReport := PlanReport{Name: plan.Name}
+		if d.showGuids {
+			planReport.GUID = plan.GUID
+		}
+
+		for _, instance := range instances {
+			if !visibility.Allowed(instance.SpaceGUID) {
+				continue
+			}
+			instanceReport := InstanceReport{
+				Name:          instance.Name,
+				Org:           instance.OrgName,
+				Space:         instance.SpaceName,
+				LastOperation: instance.LastOperation,
+			}
+			if d.showGuids {
+				instanceReport.GUID = instance.GUID
+			}
+			planReport.Instances = append(planReport.Instances, instanceReport)
+		}
+
+		if len(planReport.Instances) == 0 {
+			continue
+		}
+
+		report.Plans = append(report.Plans, planReport)
 	}
 
-	brokerGUID := curlResponse.Resources[0].Metadata["guid"]
+	report.Hidden = visibility.Summary()
 
-	var response bytes.Buffer
 	username, _ := d.cliConnection.Username()
-	response.WriteString(fmt.Sprintf("Describing broker %s as visible by %s\n\n", Entity(d.brokerName), Entity(username)))
+	d.renderer.RenderBroker(report, username)
+}
 
-	plansResponse := d.curl(fmt.Sprintf("/v2/service_plans?q=service_broker_guid:%s", brokerGUID)) //TODO: pagination
-	if plansResponse.TotalResults == 0 {
-		Warn(d.brokerName + " has no plans")
+func (d *DescribePlugin) DescribeService() {
+	space, err := d.cliConnection.GetCurrentSpace()
+	if err != nil {
+		d.renderer.Fail(err, "could not determine current space")
+	}
+
+	instance, err := d.capi.FindInstance(d.serviceName, space.Guid)
+	if err != nil {
+		d.renderer.Fail(err, "could not resolve service instance "+d.serviceName)
+	}
+	if instance.GUID == "" {
+		d.renderer.Warn(d.serviceName + " not found")
+	}
+
+	report := ServiceReport{
+		Name:          d.serviceName,
+		DashboardURL:  instance.DashboardURL,
+		Tags:          instance.Tags,
+		LastOperation: instance.LastOperation,
+	}
+	if d.showGuids {
+		report.GUID = instance.GUID
 	}
 
-	spaces, _ := d.cliConnection.GetSpaces()
-	orgs := d.getOrgs(spaces)
+	plan, err := d.capi.GetPlan(instance.PlanGUID)
+	if err != nil {
+		d.reportCCError(err, "could not resolve plan for "+d.serviceName)
+	} else {
+		report.Plan = plan.Name
+		if d.showGuids {
+			report.PlanGUID = plan.GUID
+		}
+
+		service, err := d.capi.GetService(plan.ServiceGUID)
+		if err != nil {
+			d.reportCCError(err, "could not resolve service offering for "+d.serviceName)
+		} else {
+			report.ServiceOffering = service.Label
 
-	for _, plan := range plansResponse.Resources {
-		instances := d.curl(plan.Entity["service_instances_url"].(string))
-		if instances.TotalResults > 0 {
-			response.WriteString(fmt.Sprintf("Plan %s:\n", Entity(plan.Entity["name"].(string))))
-			for _, instance := range instances.Resources {
-				response.WriteString("  ")
-				space := findSpace(spaces, instance.Entity["space_guid"].(string))
+			broker, err := d.capi.GetBrokerByGUID(service.BrokerGUID)
+			if err != nil {
+				d.reportCCError(err, "could not resolve broker for "+d.serviceName)
+			} else {
+				report.Broker = broker.Name
 				if d.showGuids {
-					response.WriteString(fmt.Sprintf("Guid: %s - ", Entity(instance.Metadata["guid"].(string))))
+					report.BrokerGUID = broker.GUID
 				}
-				response.WriteString(
-					fmt.Sprintf(
-						"Name: %s - Org: %s - Space: %s\n",
-						Entity(instance.Entity["name"].(string)),
-						Entity(orgs[space.Guid]),
-						Entity(space.Name),
-					),
-				)
 			}
 		}
 	}
 
-	fmt.Println(response.String())
+	bindings, err := d.capi.ListBindings(instance.GUID)
+	if err != nil {
+		d.reportCCError(err, "could not list bindings for "+d.serviceName)
+	} else {
+		for _, binding := range bindings {
+			boundApp := BoundAppReport{Name: binding.AppName}
+			if d.showGuids {
+				boundApp.BindingGUID = binding.GUID
+			}
+			report.BoundApps = append(report.BoundApps, boundApp)
+		}
+	}
+
+	username, _ := d.cliConnection.Username()
+	d.renderer.RenderService(report, username)
+}
+
+// reportCCError triages a CC error the way DescribeBroker/DescribeService
+// need to: not-found and forbidden responses are surfaced as notices so the
+// rest of the report still renders, while anything else (a real transport or
+// server error) still aborts via Fail. Returns true when the error was
+// handled as a notice, so callers know the affected piece of work was
+// skipped rather than the whole command failing.
+func (d *DescribePlugin) reportCCError(err error, context string) bool {
+	ccErr, ok := err.(*CCError)
+	if !ok {
+		d.renderer.Fail(err, context)
+		return false
+	}
+
+	switch {
+	case ccErr.NotFound():
+		d.renderer.Notice(context + ": not found")
+	case ccErr.Forbidden():
+		d.renderer.Notice(context + ": " + ccErr.Description)
+	default:
+		d.renderer.Fail(err, context)
+		return false
+	}
+	return true
+}
+
+// newVisibilityFilter builds the caller-visibility filter for DescribeBroker,
+// walking whichever API version's role endpoints d.capi actually speaks.
+func (d *DescribePlugin) newVisibilityFilter() *VisibilityFilter {
+	if !d.asUser || d.admin || isAdmin(d.cliConnection) {
+		return &VisibilityFilter{}
+	}
+
+	var visibleSpaces map[string]bool
+	switch c := d.capi.(type) {
+	case *v2Client:
+		visibleSpaces = reachableSpaces(c.http, d.cliConnection)
+	case *v3Client:
+		visibleSpaces = reachableSpacesV3(c.http, d.cliConnection)
+	default:
+		return &VisibilityFilter{}
+	}
+
+	return &VisibilityFilter{
+		enabled:       true,
+		visibleSpaces: visibleSpaces,
+		hiddenSpaces:  map[string]bool{},
+	}
+}
+
+// adminScope is the UAA scope CF grants only to admins, the same scope the
+// CF OAuth flow itself checks to decide whether to show admin-only UI.
+const adminScope = "cloud_controller.admin"
+
+// isAdmin decodes the caller's OAuth token and checks for adminScope.
+// /v2/config/feature_flags is readable by any authenticated user, not just
+// admins, so it can't be used as an admin signal; the scope claim is the
+// real one. Fails closed: any error reading or decoding the token means "not
+// admin", since treating "we couldn't tell" as "admin" would disable the
+// visibility filter exactly when we have the least information about the
+// caller.
+func isAdmin(cliConnection plugin.CliConnection) bool {
+	token, err := cliConnection.AccessToken()
+	if err != nil || token == "" {
+		return false
+	}
+	return tokenHasScope(token, adminScope)
+}
+
+// tokenHasScope reports whether a "bearer <jwt>" access token carries scope.
+// The token's signature isn't (and can't be) verified here: UAA already
+// verified it before the CLI ever saw it, and CliConnection gives no way to
+// fetch UAA's signing key to re-verify it ourselves.
+func tokenHasScope(token, scope string) bool {
+	token = strings.TrimPrefix(token, "bearer ")
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return false
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return false
+	}
+
+	var claims struct {
+		Scope []string `json:"scope"`
+	}
+	if err := json.Unmarshal(payload, &claims); err != nil {
+		return false
+	}
+
+	for _, s := range claims.Scope {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
+// reachableSpaces unions the spaces the caller can reach as developer,
+// manager, or auditor, the same role endpoints the CF OAuth flow walks to
+// compute effective visibility.
+func reachableSpaces(http *httpClient, cliConnection plugin.CliConnection) map[string]bool {
+	userGUID, err := cliConnection.UserGuid()
+	if err != nil || userGUID == "" {
+		return map[string]bool{}
+	}
+
+	spaces := map[string]bool{}
+	for _, roleEndpoint := range []string{"spaces", "managed_spaces", "audited_spaces"} {
+		resp, err := http.curlPaged(fmt.Sprintf("/v2/users/%s/%s", userGUID, roleEndpoint), 100)
+		if err != nil {
+			continue
+		}
+		for _, space := range resp.Resources {
+			spaces[space.Metadata["guid"].(string)] = true
+		}
+	}
+	return spaces
+}
+
+// reachableSpacesV3 is reachableSpaces for CAPI v3: a single /v3/roles query
+// for the caller's space_developer/space_manager/space_auditor roles, each of
+// which relates straight to a space guid, instead of v2's one-request-per-role
+// endpoints.
+func reachableSpacesV3(http *httpClient, cliConnection plugin.CliConnection) map[string]bool {
+	userGUID, err := cliConnection.UserGuid()
+	if err != nil || userGUID == "" {
+		return map[string]bool{}
+	}
+
+	resp, err := http.curlV3(fmt.Sprintf("/v3/roles?user_guids=%s&types=space_developer,space_manager,space_auditor", userGUID))
+	if err != nil {
+		return map[string]bool{}
+	}
+
+	spaces := map[string]bool{}
+	for _, role := range resp.Resources {
+		if spaceGUID := relationshipGUID(role, "space"); spaceGUID != "" {
+			spaces[spaceGUID] = true
+		}
+	}
+	return spaces
+}
+
+// VisibilityFilter narrows a broker report down to what the calling user can
+// actually see. The zero value is a no-op filter that allows everything,
+// which is what admins and -as-user=false get.
+type VisibilityFilter struct {
+	enabled       bool
+	visibleSpaces map[string]bool
+	hiddenSpaces  map[string]bool
+	hiddenCount   int
+}
+
+func (f *VisibilityFilter) Allowed(spaceGUID string) bool {
+	if !f.enabled || f.visibleSpaces[spaceGUID] {
+		return true
+	}
+	f.hiddenCount++
+	f.hiddenSpaces[spaceGUID] = true
+	return false
+}
+
+// Summary describes how much was hidden, or "" if nothing was.
+func (f *VisibilityFilter) Summary() string {
+	if !f.enabled || f.hiddenCount == 0 {
+		return ""
+	}
+	return fmt.Sprintf("hidden %d instances in %d spaces you cannot access", f.hiddenCount, len(f.hiddenSpaces))
+}
+
+// CAPIClient abstracts over the Cloud Controller API version a foundation
+// speaks, so DescribeBroker/DescribeService don't need to know whether
+// they're talking to CF-for-VMs' v2 API or Korifi/CAPI v3.
+type CAPIClient interface {
+	GetBroker(name string) (BrokerInfo, error)
+	GetBrokerByGUID(guid string) (BrokerInfo, error)
+	ListPlans(brokerGUID string) ([]PlanInfo, error)
+	GetPlan(guid string) (PlanInfo, error)
+	GetService(guid string) (ServiceInfo, error)
+	ListInstances(planGUID string) ([]InstanceInfo, error)
+	FindInstance(name, spaceGUID string) (InstanceInfo, error)
+	ListBindings(instanceGUID string) ([]BindingInfo, error)
+}
+
+type BrokerInfo struct {
+	GUID string
+	Name string
+}
+
+type PlanInfo struct {
+	GUID        string
+	Name        string
+	ServiceGUID string
+}
+
+type ServiceInfo struct {
+	GUID       string
+	Label      string
+	BrokerGUID string
+}
+
+type InstanceInfo struct {
+	GUID          string
+	Name          string
+	SpaceGUID     string
+	OrgName       string
+	SpaceName     string
+	PlanGUID      string
+	DashboardURL  string
+	Tags          []string
+	LastOperation LastOperationReport
+}
+
+type BindingInfo struct {
+	GUID    string
+	AppName string
+}
+
+// newCAPIClient picks a v2 or v3 client. An explicit apiVersion ("v2"/"v3")
+// wins; otherwise it probes /v3/info, which only CAPI v3-speaking
+// foundations (Korifi, and CF-for-VMs since it grew a v3 API) answer with a
+// resource-shaped body.
+func newCAPIClient(cliConnection plugin.CliConnection, apiVersion string) CAPIClient {
+	http := &httpClient{cliConnection: cliConnection}
+
+	switch apiVersion {
+	case "v2":
+		return &v2Client{http: http, cliConnection: cliConnection}
+	case "v3":
+		return &v3Client{http: http}
+	default:
+		if probeV3(http) {
+			return &v3Client{http: http}
+		}
+		return &v2Client{http: http, cliConnection: cliConnection}
+	}
+}
+
+func probeV3(http *httpClient) bool {
+	var probe struct {
+		Links map[string]interface{} `json:"links"`
+	}
+	err := json.Unmarshal(http.rawCurl("/v3/info"), &probe)
+	return err == nil && len(probe.Links) > 0
+}
+
+// httpClient wraps the plugin's "cf curl" access with the v2/v3 response
+// decoding both clients need.
+type httpClient struct {
+	cliConnection plugin.CliConnection
+}
+
+func (h *httpClient) rawCurl(endpoint string) []byte {
+	raw, _ := h.rawCurlE(endpoint)
+	return raw
+}
+
+// rawCurlE is rawCurl but also surfaces the CLI RPC error, so a caller can
+// tell "curl failed" apart from "curl succeeded and said no". rawCurl is its
+// only caller and discards that error.
+func (h *httpClient) rawCurlE(endpoint string) ([]byte, error) {
+	response, err := h.cliConnection.CliCommandWithoutTerminalOutput("curl", endpoint)
+	return []byte(strings.Join(response, "")), err
+}
+
+// CCErrorResponse is the error shape CC returns in place of the requested
+// resource, e.g. {"code":170007,"error_code":"CF-ServiceBrokerNotFound","description":"..."}.
+type CCErrorResponse struct {
+	Code        int    `json:"code"`
+	ErrorCode   string `json:"error_code"`
+	Description string `json:"description"`
+}
+
+// CCError is a decoded CCErrorResponse, returned by the curl helpers instead
+// of being silently treated as an empty result set.
+type CCError struct {
+	Code        int
+	ErrorCode   string
+	Description string
+}
+
+func (e *CCError) Error() string {
+	return fmt.Sprintf("%s: %s", e.ErrorCode, e.Description)
+}
+
+// NotFound reports whether CC rejected the request because the resource
+// doesn't exist, as opposed to a permissions problem.
+func (e *CCError) NotFound() bool {
+	return strings.Contains(e.ErrorCode, "NotFound")
+}
+
+// Forbidden reports whether CC rejected the request because the caller
+// lacks permission to see the resource.
+func (e *CCError) Forbidden() bool {
+	return e.ErrorCode == "CF-NotAuthorized" || e.ErrorCode == "CF-InsufficientScope" || strings.Contains(e.ErrorCode, "Forbidden")
+}
+
+// decodeCCError checks whether raw is a CC error envelope rather than the
+// resource payload the caller asked for.
+func decodeCCError(raw []byte) *CCError {
+	var envelope CCErrorResponse
+	if err := json.Unmarshal(raw, &envelope); err != nil || envelope.Code == 0 {
+		return nil
+	}
+	return &CCError{Code: envelope.Code, ErrorCode: envelope.ErrorCode, Description: envelope.Description}
+}
+
+// CCErrorV3Entry is a single error in a CAPI v3 {"errors":[...]} envelope,
+// e.g. {"code":10010,"title":"CF-ResourceNotFound","detail":"..."}.
+type CCErrorV3Entry struct {
+	Code   int    `json:"code"`
+	Title  string `json:"title"`
+	Detail string `json:"detail"`
+}
+
+type CCErrorV3Response struct {
+	Errors []CCErrorV3Entry `json:"errors"`
+}
+
+// decodeCCErrorV3 is decodeCCError for the CAPI v3 error shape, which wraps
+// errors in an "errors" array instead of v2's flat code/error_code/description
+// fields. Only the first error is surfaced, matching how CC itself treats the
+// first entry as the primary failure.
+func decodeCCErrorV3(raw []byte) *CCError {
+	var envelope CCErrorV3Response
+	if err := json.Unmarshal(raw, &envelope); err != nil || len(envelope.Errors) == 0 {
+		return nil
+	}
+	first := envelope.Errors[0]
+	return &CCError{Code: first.Code, ErrorCode: first.Title, Description: first.Detail}
+}
+
+type CurlResponse struct {
+	TotalResults int     `json:"total_results"`
+	TotalPages   int     `json:"total_pages"`
+	NextURL      *string `json:"next_url"`
+	Resources    []struct {
+		Metadata map[string]interface{} `json:"metadata"`
+		Entity   map[string]interface{} `json:"entity"`
+	} `json:"resources"`
+}
+
+type CurlResource struct {
+	Metadata map[string]interface{} `json:"metadata"`
+	Entity   map[string]interface{} `json:"entity"`
+}
+
+// curl fetches endpoint and transparently follows next_url so callers always
+// see every resource across all pages. It returns a *CCError instead of a
+// zero-value response when CC answers with an error envelope, so callers can
+// tell "not found" and "forbidden" apart from an actually-empty result set.
+func (h *httpClient) curl(endpoint string) (CurlResponse, error) {
+	var combined CurlResponse
+	next := &endpoint
+	for next != nil {
+		raw := h.rawCurl(*next)
+		if ccErr := decodeCCError(raw); ccErr != nil {
+			return CurlResponse{}, ccErr
+		}
+		var page CurlResponse
+		if err := json.Unmarshal(raw, &page); err != nil {
+			return CurlResponse{}, err
+		}
+		combined.TotalResults = page.TotalResults
+		combined.TotalPages = page.TotalPages
+		combined.Resources = append(combined.Resources, page.Resources...)
+		next = page.NextURL
+	}
+	return combined, nil
+}
+
+// curlPaged is like curl but requests perPage results at a time, useful for
+// endpoints that can return large result sets.
+func (h *httpClient) curlPaged(endpoint string, perPage int) (CurlResponse, error) {
+	return h.curl(withResultsPerPage(endpoint, perPage))
+}
+
+func withResultsPerPage(endpoint string, perPage int) string {
+	parsed, err := url.Parse(endpoint)
+	if err != nil {
+		return endpoint
+	}
+	query := parsed.Query()
+	query.Set("results-per-page", strconv.Itoa(perPage))
+	parsed.RawQuery = query.Encode()
+	return parsed.String()
+}
+
+func (h *httpClient) curlResource(endpoint string) (CurlResource, error) {
+	raw := h.rawCurl(endpoint)
+	if ccErr := decodeCCError(raw); ccErr != nil {
+		return CurlResource{}, ccErr
+	}
+	var resource CurlResource
+	if err := json.Unmarshal(raw, &resource); err != nil {
+		return CurlResource{}, err
+	}
+	return resource, nil
+}
+
+// v3Resource is a single v3 API resource: a flat bag of fields plus
+// "relationships" and "links", as opposed to v2's metadata/entity split.
+type v3Resource map[string]interface{}
+
+type v3ListResponse struct {
+	Resources  []v3Resource            `json:"resources"`
+	Included   map[string][]v3Resource `json:"included"`
+	Pagination struct {
+		Next *struct {
+			Href string `json:"href"`
+		} `json:"next"`
+	} `json:"pagination"`
+}
+
+// curlV3 follows pagination.next.href and merges "included" resources across
+// pages, mirroring curl's next_url handling for the v3 response shape. Like
+// curl, it returns a *CCError instead of a zero-value response when CC
+// answers with a v3 {"errors":[...]} envelope.
+func (h *httpClient) curlV3(endpoint string) (v3ListResponse, error) {
+	combined := v3ListResponse{Included: map[string][]v3Resource{}}
+	next := &endpoint
+	for next != nil {
+		raw := h.rawCurl(*next)
+		if ccErr := decodeCCErrorV3(raw); ccErr != nil {
+			return v3ListResponse{}, ccErr
+		}
+		var page v3ListResponse
+		if err := json.Unmarshal(raw, &page); err != nil {
+			return v3ListResponse{}, err
+		}
+		combined.Resources = append(combined.Resources, page.Resources...)
+		for kind, resources := range page.Included {
+			combined.Included[kind] = append(combined.Included[kind], resources...)
+		}
+		if page.Pagination.Next != nil {
+			href := page.Pagination.Next.Href
+			next = &href
+		} else {
+			next = nil
+		}
+	}
+	return combined, nil
+}
+
+func (h *httpClient) curlV3Resource(endpoint string) (v3Resource, error) {
+	raw := h.rawCurl(endpoint)
+	if ccErr := decodeCCErrorV3(raw); ccErr != nil {
+		return nil, ccErr
+	}
+	var resource v3Resource
+	if err := json.Unmarshal(raw, &resource); err != nil {
+		return nil, err
+	}
+	return resource, nil
+}
+
+func stringField(res v3Resource, key string) string {
+	s, _ := res[key].(string)
+	return s
+}
+
+func stringSliceField(res v3Resource, key string) []string {
+	raw, ok := res[key].([]interface{})
+	if !ok {
+		return nil
+	}
+	values := make([]string, len(raw))
+	for i, v := range raw {
+		values[i] = fmt.Sprintf("%v", v)
+	}
+	return values
+}
+
+func lastOperationField(res v3Resource) LastOperationReport {
+	lastOperation, ok := res["last_operation"].(map[string]interface{})
+	if !ok {
+		return LastOperationReport{}
+	}
+	return LastOperationReport{
+		Type:        stringField(lastOperation, "type"),
+		State:       stringField(lastOperation, "state"),
+		Description: stringField(lastOperation, "description"),
+		UpdatedAt:   stringField(lastOperation, "updated_at"),
+	}
+}
+
+// relationshipGUID reads relationships.<name>.data.guid, the standard v3 way
+// to reference another resource.
+func relationshipGUID(res v3Resource, name string) string {
+	relationships, ok := res["relationships"].(map[string]interface{})
+	if !ok {
+		return ""
+	}
+	relationship, ok := relationships[name].(map[string]interface{})
+	if !ok {
+		return ""
+	}
+	data, ok := relationship["data"].(map[string]interface{})
+	if !ok || data == nil {
+		return ""
+	}
+	guid, _ := data["guid"].(string)
+	return guid
+}
+
+// v2Client talks to the classic CF-for-VMs v2 API.
+type v2Client struct {
+	http          *httpClient
+	cliConnection plugin.CliConnection
+
+	// spaces/orgs cache the bulk space/org resolution ListInstances needs,
+	// since DescribeBroker calls it once per plan and the result is the same
+	// every time: the space and org lists don't change mid-command.
+	spacesLoaded bool
+	spaces       []plugin_models.GetSpaces_Model
+	orgs         map[string]string
+}
+
+func (c *v2Client) GetBroker(name string) (BrokerInfo, error) {
+	resp, err := c.http.curl(fmt.Sprintf("/v2/service_brokers?q=name:%s", url.QueryEscape(name)))
+	if err != nil {
+		return BrokerInfo{}, err
+	}
+	if resp.TotalResults == 0 {
+		return BrokerInfo{}, nil
+	}
+	res := resp.Resources[0]
+	return BrokerInfo{GUID: res.Metadata["guid"].(string), Name: res.Entity["name"].(string)}, nil
+}
+
+func (c *v2Client) GetBrokerByGUID(guid string) (BrokerInfo, error) {
+	res, err := c.http.curlResource(fmt.Sprintf("/v2/service_brokers/%s", guid))
+	if err != nil {
+		return BrokerInfo{}, err
+	}
+	return BrokerInfo{GUID: res.Metadata["guid"].(string), Name: res.Entity["name"].(string)}, nil
+}
+
+func (c *v2Client) ListPlans(brokerGUID string) ([]PlanInfo, error) {
+	resp, err := c.http.curlPaged(fmt.Sprintf("/v2/service_plans?q=service_broker_guid:%s", brokerGUID), 100)
+	if err != nil {
+		return nil, err
+	}
+	plans := make([]PlanInfo, 0, len(resp.Resources))
+	for _, res := range resp.Resources {
+		plans = append(plans, PlanInfo{
+			GUID: res.Metadata["guid"].(string),
+			Name: res.Entity["name"].(string),
+		})
+	}
+	return plans, nil
+}
+
+func (c *v2Client) GetPlan(guid string) (PlanInfo, error) {
+	res, err := c.http.curlResource(fmt.Sprintf("/v2/service_plans/%s", guid))
+	if err != nil {
+		return PlanInfo{}, err
+	}
+	return PlanInfo{
+		GUID:        res.Metadata["guid"].(string),
+		Name:        res.Entity["name"].(string),
+		ServiceGUID: res.Entity["service_guid"].(string),
+	}, nil
+}
+
+func (c *v2Client) GetService(guid string) (ServiceInfo, error) {
+	res, err := c.http.curlResource(fmt.Sprintf("/v2/services/%s", guid))
+	if err != nil {
+		return ServiceInfo{}, err
+	}
+	return ServiceInfo{
+		GUID:       res.Metadata["guid"].(string),
+		Label:      res.Entity["label"].(string),
+		BrokerGUID: res.Entity["service_broker_guid"].(string),
+	}, nil
+}
+
+func (c *v2Client) ListInstances(planGUID string) ([]InstanceInfo, error) {
+	resp, err := c.http.curlPaged(fmt.Sprintf("/v2/service_plans/%s/service_instances", planGUID), 100)
+	if err != nil {
+		return nil, err
+	}
+	if len(resp.Resources) == 0 {
+		return nil, nil
+	}
+
+	spaces, orgs, err := c.spacesAndOrgs()
+	if err != nil {
+		return nil, err
+	}
+
+	instances := make([]InstanceInfo, 0, len(resp.Resources))
+	for _, res := range resp.Resources {
+		spaceGUID := res.Entity["space_guid"].(string)
+		space := findSpace(spaces, spaceGUID)
+		instances = append(instances, InstanceInfo{
+			GUID:          res.Metadata["guid"].(string),
+			Name:          res.Entity["name"].(string),
+			SpaceGUID:     spaceGUID,
+			OrgName:       orgs[spaceGUID],
+			SpaceName:     space.Name,
+			PlanGUID:      planGUID,
+			LastOperation: lastOperationFromV2Entity(res.Entity),
+		})
+	}
+	return instances, nil
+}
+
+func (c *v2Client) FindInstance(name, spaceGUID string) (InstanceInfo, error) {
+	resp, err := c.http.curlPaged(fmt.Sprintf("/v2/service_instances?q=name:%s&q=space_guid:%s", url.QueryEscape(name), spaceGUID), 100)
+	if err != nil {
+		return InstanceInfo{}, err
+	}
+	if resp.TotalResults == 0 {
+		return InstanceInfo{}, nil
+	}
+
+	res := resp.Resources[0]
+	entity := res.Entity
+	instance := InstanceInfo{
+		GUID:      res.Metadata["guid"].(string),
+		Name:      entity["name"].(string),
+		SpaceGUID: spaceGUID,
+		PlanGUID:  guidFromResourceURL(entity["service_plan_url"].(string)),
+	}
+
+	if dashboardURL, ok := entity["dashboard_url"].(string); ok {
+		instance.DashboardURL = dashboardURL
+	}
+
+	if tags, ok := entity["tags"].([]interface{}); ok {
+		for _, tag := range tags {
+			instance.Tags = append(instance.Tags, fmt.Sprintf("%v", tag))
+		}
+	}
+
+	instance.LastOperation = lastOperationFromV2Entity(entity)
+
+	return instance, nil
+}
+
+// lastOperationFromV2Entity extracts last_operation from a v2 entity map, the
+// shape ListInstances and FindInstance both get back from curl.
+func lastOperationFromV2Entity(entity map[string]interface{}) LastOperationReport {
+	lastOperation, ok := entity["last_operation"].(map[string]interface{})
+	if !ok {
+		return LastOperationReport{}
+	}
+	return LastOperationReport{
+		Type:        fmt.Sprintf("%v", lastOperation["type"]),
+		State:       fmt.Sprintf("%v", lastOperation["state"]),
+		Description: fmt.Sprintf("%v", lastOperation["description"]),
+		UpdatedAt:   fmt.Sprintf("%v", lastOperation["updated_at"]),
+	}
+}
+
+func (c *v2Client) ListBindings(instanceGUID string) ([]BindingInfo, error) {
+	resp, err := c.http.curlPaged(fmt.Sprintf("/v2/service_instances/%s/service_bindings", instanceGUID), 100)
+	if err != nil {
+		return nil, err
+	}
+	bindings := make([]BindingInfo, 0, len(resp.Resources))
+	for _, res := range resp.Resources {
+		app, err := c.http.curlResource(res.Entity["app_url"].(string))
+		if err != nil {
+			return nil, err
+		}
+		bindings = append(bindings, BindingInfo{
+			GUID:    res.Metadata["guid"].(string),
+			AppName: app.Entity["name"].(string),
+		})
+	}
+	return bindings, nil
+}
+
+// guidFromResourceURL pulls the trailing guid off a v2 resource URL like
+// "/v2/service_plans/abc-123".
+func guidFromResourceURL(resourceURL string) string {
+	parts := strings.Split(resourceURL, "/")
+	return parts[len(parts)-1]
+}
+
+// spacesAndOrgs resolves the spaces and space->org name lookups ListInstances
+// needs, fetching and chunk-resolving them only once per v2Client even though
+// DescribeBroker calls ListInstances once per plan.
+func (c *v2Client) spacesAndOrgs() ([]plugin_models.GetSpaces_Model, map[string]string, error) {
+	if c.spacesLoaded {
+		return c.spaces, c.orgs, nil
+	}
+
+	spaces, _ := c.cliConnection.GetSpaces()
+	orgs, err := c.getOrgs(spaces)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	c.spaces = spaces
+	c.orgs = orgs
+	c.spacesLoaded = true
+	return spaces, orgs, nil
 }
 
-func (d *DescribePlugin) getOrgs(spaces []plugin_models.GetSpaces_Model) map[string]string {
+// guidChunkSize bounds how many guids are joined into a single "guid IN ..."
+// filter so the query string stays well under the CC API's URL length limit.
+const guidChunkSize = 50
+
+// getOrgs resolves the org name for every space with bulk lookups instead of
+// one request per space: one /v2/spaces lookup per chunk of guidChunkSize
+// space guids to learn each space's org guid, then one /v2/organizations
+// lookup per chunk of unique org guids. The number of calls still grows with
+// the number of spaces, by one per guidChunkSize spaces.
+func (c *v2Client) getOrgs(spaces []plugin_models.GetSpaces_Model) (map[string]string, error) {
+	spaceGuids := make([]string, len(spaces))
+	for i, space := range spaces {
+		spaceGuids[i] = space.Guid
+	}
+
+	orgGuidsBySpace, err := c.getOrgGuidsBySpace(spaceGuids)
+	if err != nil {
+		return nil, err
+	}
+
+	uniqueOrgGuids := map[string]bool{}
+	for _, orgGuid := range orgGuidsBySpace {
+		uniqueOrgGuids[orgGuid] = true
+	}
+	orgGuids := make([]string, 0, len(uniqueOrgGuids))
+	for orgGuid := range uniqueOrgGuids {
+		orgGuids = append(orgGuids, orgGuid)
+	}
+
+	orgNamesByGuid := map[string]string{}
+	for _, chunk := range chunkGuids(orgGuids, guidChunkSize) {
+		query := fmt.Sprintf("guid IN %s", strings.Join(chunk, ","))
+		orgsResponse, err := c.http.curlPaged(fmt.Sprintf("/v2/organizations?q=%s", url.QueryEscape(query)), 100)
+		if err != nil {
+			return nil, err
+		}
+		for _, org := range orgsResponse.Resources {
+			orgNamesByGuid[org.Metadata["guid"].(string)] = org.Entity["name"].(string)
+		}
+	}
+
 	orgs := map[string]string{}
-	for _, space := range spaces {
-		orgResponse := d.curl(fmt.Sprintf("/v2/organizations?q=space_guid:%s", space.Guid))
-		orgs[space.Guid] = orgResponse.Resources[0].Entity["name"].(string)
+	for spaceGuid, orgGuid := range orgGuidsBySpace {
+		orgs[spaceGuid] = orgNamesByGuid[orgGuid]
+	}
+	return orgs, nil
+}
+
+// getOrgGuidsBySpace looks up organization_guid for each space guid via
+// /v2/spaces, since plugin_models.GetSpaces_Model doesn't carry it.
+func (c *v2Client) getOrgGuidsBySpace(spaceGuids []string) (map[string]string, error) {
+	orgGuidsBySpace := map[string]string{}
+	for _, chunk := range chunkGuids(spaceGuids, guidChunkSize) {
+		query := fmt.Sprintf("guid IN %s", strings.Join(chunk, ","))
+		spacesResponse, err := c.http.curlPaged(fmt.Sprintf("/v2/spaces?q=%s", url.QueryEscape(query)), 100)
+		if err != nil {
+			return nil, err
+		}
+		for _, space := range spacesResponse.Resources {
+			orgGuidsBySpace[space.Metadata["guid"].(string)] = space.Entity["organization_guid"].(string)
+		}
+	}
+	return orgGuidsBySpace, nil
+}
+
+func chunkGuids(guids []string, size int) [][]string {
+	var chunks [][]string
+	for i := 0; i < len(guids); i += size {
+		end := i + size
+		if end > len(guids) {
+			end = len(guids)
+		}
+		chunks = append(chunks, guids[i:end])
 	}
-	return orgs
+	return chunks
 }
 
 func findSpace(spaces []plugin_models.GetSpaces_Model, spaceGUID string) plugin_models.GetSpaces_Model {
@@ -154,28 +1075,318 @@ func findSpace(spaces []plugin_models.GetSpaces_Model, spaceGUID string) plugin_
 	return plugin_models.GetSpaces_Model{}
 }
 
-func findOrg(orgs []plugin_models.GetOrgs_Model, orgGUID string) plugin_models.GetOrgs_Model {
-	for _, s := range orgs {
-		if s.Guid == orgGUID {
-			return s
+// v3Client talks to CAPI v3 (Korifi, and CF-for-VMs since it grew a v3 API).
+type v3Client struct {
+	http *httpClient
+}
+
+func (c *v3Client) GetBroker(name string) (BrokerInfo, error) {
+	resp, err := c.http.curlV3(fmt.Sprintf("/v3/service_brokers?names=%s", url.QueryEscape(name)))
+	if err != nil {
+		return BrokerInfo{}, err
+	}
+	if len(resp.Resources) == 0 {
+		return BrokerInfo{}, nil
+	}
+	res := resp.Resources[0]
+	return BrokerInfo{GUID: stringField(res, "guid"), Name: stringField(res, "name")}, nil
+}
+
+func (c *v3Client) GetBrokerByGUID(guid string) (BrokerInfo, error) {
+	res, err := c.http.curlV3Resource(fmt.Sprintf("/v3/service_brokers/%s", guid))
+	if err != nil {
+		return BrokerInfo{}, err
+	}
+	return BrokerInfo{GUID: stringField(res, "guid"), Name: stringField(res, "name")}, nil
+}
+
+func (c *v3Client) ListPlans(brokerGUID string) ([]PlanInfo, error) {
+	resp, err := c.http.curlV3(fmt.Sprintf("/v3/service_plans?service_broker_guids=%s", brokerGUID))
+	if err != nil {
+		return nil, err
+	}
+	plans := make([]PlanInfo, 0, len(resp.Resources))
+	for _, res := range resp.Resources {
+		plans = append(plans, PlanInfo{
+			GUID:        stringField(res, "guid"),
+			Name:        stringField(res, "name"),
+			ServiceGUID: relationshipGUID(res, "service_offering"),
+		})
+	}
+	return plans, nil
+}
+
+func (c *v3Client) GetPlan(guid string) (PlanInfo, error) {
+	res, err := c.http.curlV3Resource(fmt.Sprintf("/v3/service_plans/%s", guid))
+	if err != nil {
+		return PlanInfo{}, err
+	}
+	return PlanInfo{
+		GUID:        stringField(res, "guid"),
+		Name:        stringField(res, "name"),
+		ServiceGUID: relationshipGUID(res, "service_offering"),
+	}, nil
+}
+
+func (c *v3Client) GetService(guid string) (ServiceInfo, error) {
+	res, err := c.http.curlV3Resource(fmt.Sprintf("/v3/service_offerings/%s", guid))
+	if err != nil {
+		return ServiceInfo{}, err
+	}
+	return ServiceInfo{
+		GUID:       stringField(res, "guid"),
+		Label:      stringField(res, "name"),
+		BrokerGUID: relationshipGUID(res, "service_broker"),
+	}, nil
+}
+
+func (c *v3Client) ListInstances(planGUID string) ([]InstanceInfo, error) {
+	resp, err := c.http.curlV3(fmt.Sprintf("/v3/service_instances?service_plan_guids=%s&include=space.organization", planGUID))
+	if err != nil {
+		return nil, err
+	}
+	orgNameBySpace, spaceNameByGUID := indexSpacesAndOrgs(resp.Included)
+
+	instances := make([]InstanceInfo, 0, len(resp.Resources))
+	for _, res := range resp.Resources {
+		spaceGUID := relationshipGUID(res, "space")
+		instances = append(instances, InstanceInfo{
+			GUID:          stringField(res, "guid"),
+			Name:          stringField(res, "name"),
+			SpaceGUID:     spaceGUID,
+			OrgName:       orgNameBySpace[spaceGUID],
+			SpaceName:     spaceNameByGUID[spaceGUID],
+			PlanGUID:      planGUID,
+			DashboardURL:  stringField(res, "dashboard_url"),
+			Tags:          stringSliceField(res, "tags"),
+			LastOperation: lastOperationField(res),
+		})
+	}
+	return instances, nil
+}
+
+func (c *v3Client) FindInstance(name, spaceGUID string) (InstanceInfo, error) {
+	resp, err := c.http.curlV3(fmt.Sprintf("/v3/service_instances?names=%s&space_guids=%s", url.QueryEscape(name), spaceGUID))
+	if err != nil {
+		return InstanceInfo{}, err
+	}
+	if len(resp.Resources) == 0 {
+		return InstanceInfo{}, nil
+	}
+
+	res := resp.Resources[0]
+	return InstanceInfo{
+		GUID:          stringField(res, "guid"),
+		Name:          stringField(res, "name"),
+		SpaceGUID:     spaceGUID,
+		PlanGUID:      relationshipGUID(res, "service_plan"),
+		DashboardURL:  stringField(res, "dashboard_url"),
+		Tags:          stringSliceField(res, "tags"),
+		LastOperation: lastOperationField(res),
+	}, nil
+}
+
+func (c *v3Client) ListBindings(instanceGUID string) ([]BindingInfo, error) {
+	resp, err := c.http.curlV3(fmt.Sprintf("/v3/service_credential_bindings?service_instance_guids=%s&include=app", instanceGUID))
+	if err != nil {
+		return nil, err
+	}
+
+	appNameByGUID := map[string]string{}
+	for _, app := range resp.Included["apps"] {
+		appNameByGUID[stringField(app, "guid")] = stringField(app, "name")
+	}
+
+	bindings := make([]BindingInfo, 0, len(resp.Resources))
+	for _, res := range resp.Resources {
+		bindings = append(bindings, BindingInfo{
+			GUID:    stringField(res, "guid"),
+			AppName: appNameByGUID[relationshipGUID(res, "app")],
+		})
+	}
+	return bindings, nil
+}
+
+// indexSpacesAndOrgs turns the "included" block from
+// ?include=space.organization into space guid -> {org name, space name}
+// lookups, in place of the separate /v2/spaces and /v2/organizations lookups
+// the v2 client makes.
+func indexSpacesAndOrgs(included map[string][]v3Resource) (orgNameBySpace, spaceNameByGUID map[string]string) {
+	orgNameByGUID := map[string]string{}
+	for _, org := range included["organizations"] {
+		orgNameByGUID[stringField(org, "guid")] = stringField(org, "name")
+	}
+
+	spaceNameByGUID = map[string]string{}
+	orgNameBySpace = map[string]string{}
+	for _, space := range included["spaces"] {
+		guid := stringField(space, "guid")
+		spaceNameByGUID[guid] = stringField(space, "name")
+		orgNameBySpace[guid] = orgNameByGUID[relationshipGUID(space, "organization")]
+	}
+	return orgNameBySpace, spaceNameByGUID
+}
+
+// Renderer turns a gathered report into output. TextRenderer keeps today's
+// colored human-readable format; the structured renderers make `describe`
+// usable in scripts and pipelines.
+type Renderer interface {
+	RenderBroker(report BrokerReport, username string)
+	RenderService(report ServiceReport, username string)
+	Warn(message string)
+	Fail(err error, message string)
+	Notice(message string)
+}
+
+func newRenderer(output string) Renderer {
+	switch output {
+	case "json":
+		return structuredRenderer{format: "json"}
+	case "yaml":
+		return structuredRenderer{format: "yaml"}
+	default:
+		return textRenderer{}
+	}
+}
+
+type textRenderer struct{}
+
+func (r textRenderer) RenderBroker(report BrokerReport, username string) {
+	fmt.Printf("Describing broker %s as visible by %s\n\n", Entity(report.Name), Entity(username))
+	for _, plan := range report.Plans {
+		fmt.Printf("Plan %s:\n", Entity(plan.Name))
+		for _, instance := range plan.Instances {
+			if instance.GUID != "" {
+				fmt.Printf("  Guid: %s - ", Entity(instance.GUID))
+			} else {
+				fmt.Print("  ")
+			}
+			fmt.Printf("Name: %s - Org: %s - Space: %s\n", Entity(instance.Name), Entity(instance.Org), Entity(instance.Space))
 		}
 	}
-	return plugin_models.GetOrgs_Model{}
+	if report.Hidden != "" {
+		fmt.Printf("\n%s\n", terminal.WarningColor(report.Hidden))
+	}
 }
 
-func (d *DescribePlugin) DescribeService() {
+func (r textRenderer) RenderService(report ServiceReport, username string) {
+	fmt.Printf("Describing service %s as visible by %s\n\n", Entity(report.Name), Entity(username))
+
+	if report.GUID != "" {
+		fmt.Printf("Guid: %s\n", Entity(report.GUID))
+	}
+
+	fmt.Printf("Plan: %s\n", Entity(report.Plan))
+	if report.PlanGUID != "" {
+		fmt.Printf("Plan Guid: %s\n", Entity(report.PlanGUID))
+	}
+
+	fmt.Printf("Service Offering: %s\n", Entity(report.ServiceOffering))
 
+	fmt.Printf("Broker: %s\n", Entity(report.Broker))
+	if report.BrokerGUID != "" {
+		fmt.Printf("Broker Guid: %s\n", Entity(report.BrokerGUID))
+	}
+
+	if report.DashboardURL != "" {
+		fmt.Printf("Dashboard: %s\n", Entity(report.DashboardURL))
+	}
+
+	if len(report.Tags) > 0 {
+		fmt.Printf("Tags: %s\n", Entity(strings.Join(report.Tags, ", ")))
+	}
+
+	if report.LastOperation.State != "" {
+		fmt.Printf(
+			"Last Operation: %s - %s (%s)\n",
+			Entity(report.LastOperation.Type),
+			Entity(report.LastOperation.State),
+			Entity(report.LastOperation.UpdatedAt),
+		)
+		if report.LastOperation.Description != "" {
+			fmt.Printf("  %s\n", Entity(report.LastOperation.Description))
+		}
+	}
+
+	if len(report.BoundApps) > 0 {
+		fmt.Println("Bound Apps:")
+		for _, app := range report.BoundApps {
+			if app.BindingGUID != "" {
+				fmt.Printf("  Binding Guid: %s - ", Entity(app.BindingGUID))
+			} else {
+				fmt.Print("  ")
+			}
+			fmt.Printf("Name: %s\n", Entity(app.Name))
+		}
+	}
 }
 
-func (d *DescribePlugin) curl(endpoint string) CurlResponse {
-	brokersResponse, _ := d.cliConnection.CliCommandWithoutTerminalOutput("curl", endpoint)
+func (r textRenderer) Warn(message string) {
+	Warn(message)
+}
+
+func (r textRenderer) Fail(err error, message string) {
+	Fail(err, message)
+}
+
+// Notice prints a warning about a piece of the report that was skipped
+// without terminating, unlike Warn which always exits.
+func (r textRenderer) Notice(message string) {
+	fmt.Println(terminal.WarningColor(message))
+}
+
+// structuredMessage is what warnings and errors look like in json/yaml mode,
+// so a pipeline consuming the output (e.g. via jq) can tell a warning from a
+// report without scraping colored text.
+type structuredMessage struct {
+	Warning string `json:"warning,omitempty" yaml:"warning,omitempty"`
+	Error   string `json:"error,omitempty" yaml:"error,omitempty"`
+}
+
+// structuredRenderer serializes reports as either json or yaml and writes
+// warnings/errors to stderr instead of mixing them into stdout.
+type structuredRenderer struct {
+	format string
+}
+
+func (r structuredRenderer) marshal(v interface{}) ([]byte, error) {
+	if r.format == "yaml" {
+		return yaml.Marshal(v)
+	}
+	return json.MarshalIndent(v, "", "  ")
+}
 
-	var curlResponse CurlResponse
-	err := json.Unmarshal([]byte(strings.Join(brokersResponse, "")), &curlResponse)
+func (r structuredRenderer) write(v interface{}) {
+	data, err := r.marshal(v)
 	if err != nil {
-		Fail(err, "could not unmarshal response")
+		Fail(err, "could not render output")
 	}
-	return curlResponse
+	fmt.Println(string(data))
+}
+
+func (r structuredRenderer) RenderBroker(report BrokerReport, username string) {
+	r.write(report)
+}
+
+func (r structuredRenderer) RenderService(report ServiceReport, username string) {
+	r.write(report)
+}
+
+func (r structuredRenderer) Warn(message string) {
+	data, _ := r.marshal(structuredMessage{Warning: message})
+	fmt.Fprintln(os.Stderr, string(data))
+	os.Exit(0)
+}
+
+func (r structuredRenderer) Fail(err error, message string) {
+	data, _ := r.marshal(structuredMessage{Error: fmt.Sprintf("%s: %s", message, err.Error())})
+	fmt.Fprintln(os.Stderr, string(data))
+	os.Exit(1)
+}
+
+func (r structuredRenderer) Notice(message string) {
+	data, _ := r.marshal(structuredMessage{Warning: message})
+	fmt.Fprintln(os.Stderr, string(data))
 }
 
 func Entity(s string) string {
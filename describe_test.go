@@ -0,0 +1,247 @@
+package main
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"code.cloudfoundry.org/cli/plugin"
+)
+
+// fakeCliConnection embeds plugin.CliConnection so tests only need to
+// implement the one or two methods a given test actually exercises.
+type fakeCliConnection struct {
+	plugin.CliConnection
+	accessToken string
+	accessErr   error
+}
+
+func (f fakeCliConnection) AccessToken() (string, error) {
+	return f.accessToken, f.accessErr
+}
+
+func fakeJWT(scopes []string) string {
+	header := base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"RS256"}`))
+	claims, _ := json.Marshal(struct {
+		Scope []string `json:"scope"`
+	}{scopes})
+	payload := base64.RawURLEncoding.EncodeToString(claims)
+	return "bearer " + header + "." + payload + ".signature"
+}
+
+func TestTokenHasScope(t *testing.T) {
+	cases := []struct {
+		name  string
+		token string
+		want  bool
+	}{
+		{"admin scope present", fakeJWT([]string{"cloud_controller.read", adminScope}), true},
+		{"non-admin developer token", fakeJWT([]string{"cloud_controller.read", "cloud_controller.write"}), false},
+		{"no scopes at all", fakeJWT(nil), false},
+		{"malformed token", "bearer not-a-jwt", false},
+		{"empty token", "", false},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if got := tokenHasScope(c.token, adminScope); got != c.want {
+				t.Errorf("tokenHasScope(%q) = %v, want %v", c.token, got, c.want)
+			}
+		})
+	}
+}
+
+func TestIsAdmin(t *testing.T) {
+	cases := []struct {
+		name string
+		conn fakeCliConnection
+		want bool
+	}{
+		{"admin token", fakeCliConnection{accessToken: fakeJWT([]string{adminScope})}, true},
+		{"non-admin token", fakeCliConnection{accessToken: fakeJWT([]string{"cloud_controller.write"})}, false},
+		{"AccessToken errors", fakeCliConnection{accessErr: errors.New("not logged in")}, false},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if got := isAdmin(c.conn); got != c.want {
+				t.Errorf("isAdmin() = %v, want %v", got, c.want)
+			}
+		})
+	}
+}
+
+func TestDecodeCCError(t *testing.T) {
+	cases := []struct {
+		name string
+		raw  string
+		want *CCError
+	}{
+		{
+			"v2 error envelope",
+			`{"code":170007,"error_code":"CF-ServiceBrokerNotFound","description":"broker not found"}`,
+			&CCError{Code: 170007, ErrorCode: "CF-ServiceBrokerNotFound", Description: "broker not found"},
+		},
+		{"resource payload, not an error", `{"metadata":{"guid":"abc"},"entity":{"name":"my-broker"}}`, nil},
+		{"empty response", ``, nil},
+		{"not json at all", `not json`, nil},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			got := decodeCCError([]byte(c.raw))
+			assertCCErrorEqual(t, got, c.want)
+		})
+	}
+}
+
+func TestDecodeCCErrorV3(t *testing.T) {
+	cases := []struct {
+		name string
+		raw  string
+		want *CCError
+	}{
+		{
+			"v3 error envelope",
+			`{"errors":[{"code":10010,"title":"CF-ResourceNotFound","detail":"service broker not found"}]}`,
+			&CCError{Code: 10010, ErrorCode: "CF-ResourceNotFound", Description: "service broker not found"},
+		},
+		{"list payload, not an error", `{"resources":[{"guid":"abc"}]}`, nil},
+		{"errors array present but empty", `{"errors":[]}`, nil},
+		{"empty response", ``, nil},
+		{"not json at all", `not json`, nil},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			got := decodeCCErrorV3([]byte(c.raw))
+			assertCCErrorEqual(t, got, c.want)
+		})
+	}
+}
+
+func assertCCErrorEqual(t *testing.T, got, want *CCError) {
+	t.Helper()
+	if (got == nil) != (want == nil) {
+		t.Fatalf("got %+v, want %+v", got, want)
+	}
+	if got == nil {
+		return
+	}
+	if *got != *want {
+		t.Fatalf("got %+v, want %+v", *got, *want)
+	}
+}
+
+func TestVisibilityFilterAllowed(t *testing.T) {
+	t.Run("zero value allows everything", func(t *testing.T) {
+		f := &VisibilityFilter{}
+		if !f.Allowed("space-a") {
+			t.Error("zero-value filter should allow every space")
+		}
+		if f.Summary() != "" {
+			t.Errorf("zero-value filter Summary() = %q, want empty", f.Summary())
+		}
+	})
+
+	t.Run("enabled filter allows visible spaces and hides the rest", func(t *testing.T) {
+		f := &VisibilityFilter{
+			enabled:       true,
+			visibleSpaces: map[string]bool{"space-a": true},
+			hiddenSpaces:  map[string]bool{},
+		}
+
+		if !f.Allowed("space-a") {
+			t.Error("space-a should be allowed")
+		}
+		if f.Allowed("space-b") {
+			t.Error("space-b should be hidden")
+		}
+		if f.Allowed("space-c") {
+			t.Error("space-c should be hidden")
+		}
+
+		want := "hidden 2 instances in 2 spaces you cannot access"
+		if got := f.Summary(); got != want {
+			t.Errorf("Summary() = %q, want %q", got, want)
+		}
+	})
+
+	t.Run("enabled filter with nothing hidden reports no summary", func(t *testing.T) {
+		f := &VisibilityFilter{
+			enabled:       true,
+			visibleSpaces: map[string]bool{"space-a": true},
+			hiddenSpaces:  map[string]bool{},
+		}
+		f.Allowed("space-a")
+
+		if got := f.Summary(); got != "" {
+			t.Errorf("Summary() = %q, want empty", got)
+		}
+	})
+}
+
+func TestChunkGuids(t *testing.T) {
+	cases := []struct {
+		name string
+		in   []string
+		size int
+		want [][]string
+	}{
+		{"empty input", nil, 50, nil},
+		{"fits in one chunk", []string{"a", "b", "c"}, 50, [][]string{{"a", "b", "c"}}},
+		{"splits on a chunk boundary", []string{"a", "b", "c", "d"}, 2, [][]string{{"a", "b"}, {"c", "d"}}},
+		{"last chunk is a partial remainder", []string{"a", "b", "c"}, 2, [][]string{{"a", "b"}, {"c"}}},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			got := chunkGuids(c.in, c.size)
+			if len(got) != len(c.want) {
+				t.Fatalf("chunkGuids(%v, %d) = %v, want %v", c.in, c.size, got, c.want)
+			}
+			for i := range got {
+				if len(got[i]) != len(c.want[i]) {
+					t.Fatalf("chunk %d = %v, want %v", i, got[i], c.want[i])
+				}
+				for j := range got[i] {
+					if got[i][j] != c.want[i][j] {
+						t.Fatalf("chunk %d = %v, want %v", i, got[i], c.want[i])
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestWithResultsPerPage(t *testing.T) {
+	cases := []struct {
+		name     string
+		endpoint string
+		perPage  int
+		want     string
+	}{
+		{"no existing query", "/v2/service_plans", 100, "/v2/service_plans?results-per-page=100"},
+		{
+			"existing query is preserved",
+			"/v2/service_plans?q=service_broker_guid:abc",
+			50,
+			"/v2/service_plans?q=service_broker_guid%3Aabc&results-per-page=50",
+		},
+		{
+			"results-per-page already set is overridden",
+			"/v2/service_plans?results-per-page=10",
+			100,
+			"/v2/service_plans?results-per-page=100",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if got := withResultsPerPage(c.endpoint, c.perPage); got != c.want {
+				t.Errorf("withResultsPerPage(%q, %d) = %q, want %q", c.endpoint, c.perPage, got, c.want)
+			}
+		})
+	}
+}